@@ -0,0 +1,260 @@
+// Package bazaar is a client for the Hypixel Skyblock bazaar API: listing
+// tradable product IDs and fetching a product's current buy/sell prices.
+package bazaar
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultEndpoint = "https://api.hypixel.net/skyblock/bazaar"
+
+	requestTimeout   = 10 * time.Second
+	maxFetchAttempts = 5
+	baseBackoff      = 500 * time.Millisecond
+	maxBackoff       = 30 * time.Second
+)
+
+var (
+	ErrBadStatus    = errors.New("api responded with non 200 status code")
+	ErrUnsuccessful = errors.New("api responded with bad status")
+)
+
+// Price is a product's latest buy/sell values as reported by the API.
+type Price struct {
+	Buy  float64
+	Sell float64
+}
+
+// API is the subset of Client's behavior callers depend on, so tests (and
+// other callers) can substitute a fake implementation.
+type API interface {
+	Products(ctx context.Context) ([]string, error)
+	Product(ctx context.Context, productId string) (Price, error)
+}
+
+// Client talks to the Hypixel bazaar API, retrying transient failures with
+// jittered exponential backoff and honoring the API's rate-limit hints.
+type Client struct {
+	endpoint   string
+	apiKey     string
+	httpClient *http.Client
+
+	rateLimitLock sync.Mutex
+	rateRemaining int
+	rateReset     time.Time
+}
+
+var _ API = (*Client)(nil)
+
+// NewClient builds a Client for DefaultEndpoint using apiKey.
+func NewClient(apiKey string) *Client {
+	return &Client{
+		endpoint:   DefaultEndpoint,
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Pace reports how long a caller should wait before its next request,
+// spreading the calls Hypixel says remain in the current rate-limit window
+// evenly across that window. It returns fallback once the window has
+// elapsed or before any response has reported rate-limit headers.
+func (c *Client) Pace(fallback time.Duration) time.Duration {
+	c.rateLimitLock.Lock()
+	remaining, reset := c.rateRemaining, c.rateReset
+	c.rateLimitLock.Unlock()
+
+	if remaining <= 0 || reset.IsZero() {
+		return fallback
+	}
+
+	until := time.Until(reset)
+	if until <= 0 {
+		return fallback
+	}
+
+	return until / time.Duration(remaining)
+}
+
+// RateLimited reports whether the most recent response indicated the
+// current rate-limit window is exhausted and hasn't reset yet.
+func (c *Client) RateLimited() bool {
+	c.rateLimitLock.Lock()
+	defer c.rateLimitLock.Unlock()
+	return c.rateRemaining <= 0 && time.Now().Before(c.rateReset)
+}
+
+// recordRateLimit remembers the RateLimit-Remaining/RateLimit-Reset values
+// from the most recent response, so Pace can spread remaining requests
+// across the rest of the window instead of assuming a fixed cadence.
+func (c *Client) recordRateLimit(h http.Header) {
+	remaining, err := strconv.Atoi(h.Get("RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+
+	resetSecs, err := strconv.Atoi(h.Get("RateLimit-Reset"))
+	if err != nil {
+		return
+	}
+
+	c.rateLimitLock.Lock()
+	c.rateRemaining = remaining
+	c.rateReset = time.Now().Add(time.Duration(resetSecs) * time.Second)
+	c.rateLimitLock.Unlock()
+}
+
+type productsResponse struct {
+	Success    bool     `json:"success"`
+	ProductIds []string `json:"productIds"`
+}
+
+type productResponse struct {
+	Success bool `json:"success"`
+	Info    struct {
+		Recap struct {
+			Buy  float64 `json:"buyPrice"`
+			Sell float64 `json:"sellPrice"`
+		} `json:"quick_status"`
+	} `json:"product_info"`
+}
+
+// Products returns the list of product IDs currently tradable on the
+// bazaar.
+func (c *Client) Products(ctx context.Context) ([]string, error) {
+	url := fmt.Sprintf("%s/products?key=%s", c.endpoint, c.apiKey)
+	data, err := c.doRequest(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	list := new(productsResponse)
+	if err := json.Unmarshal(data, list); err != nil {
+		return nil, err
+	}
+	if !list.Success {
+		return nil, ErrUnsuccessful
+	}
+
+	return list.ProductIds, nil
+}
+
+// Product returns productId's current buy/sell price.
+func (c *Client) Product(ctx context.Context, productId string) (Price, error) {
+	url := fmt.Sprintf("%s/product?key=%s&productId=%s", c.endpoint, c.apiKey, productId)
+	data, err := c.doRequest(ctx, url)
+	if err != nil {
+		return Price{}, err
+	}
+
+	info := new(productResponse)
+	if err := json.Unmarshal(data, info); err != nil {
+		return Price{}, err
+	}
+	if !info.Success {
+		return Price{}, ErrUnsuccessful
+	}
+
+	return Price{Buy: info.Info.Recap.Buy, Sell: info.Info.Recap.Sell}, nil
+}
+
+// backoffDuration returns a jittered exponential backoff for the given
+// (1-indexed) retry attempt, capped at maxBackoff.
+func backoffDuration(attempt int) time.Duration {
+	d := baseBackoff * time.Duration(uint64(1)<<uint(attempt-1))
+	if d > maxBackoff {
+		d = maxBackoff
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d)/2+1))
+}
+
+// parseRetryAfter reads the Retry-After header, which the Hypixel API sends
+// as either a number of seconds or an HTTP date.
+func parseRetryAfter(h http.Header) time.Duration {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
+
+// attemptRequest performs a single GET, reporting how long the caller
+// should wait before retrying (from Retry-After or RateLimit-Remaining)
+// alongside any error.
+func (c *Client) attemptRequest(ctx context.Context, url string) (data []byte, retryAfter time.Duration, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	c.recordRateLimit(resp.Header)
+
+	retryAfter = parseRetryAfter(resp.Header)
+	if remaining, convErr := strconv.Atoi(resp.Header.Get("RateLimit-Remaining")); convErr == nil && remaining <= 0 && retryAfter == 0 {
+		retryAfter = time.Minute
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, retryAfter, fmt.Errorf("%s: %d", ErrBadStatus.Error(), resp.StatusCode)
+	}
+
+	data, err = ioutil.ReadAll(resp.Body)
+	return data, retryAfter, err
+}
+
+// doRequest performs a GET against url, retrying transient failures
+// (non-200 responses, network errors) with jittered exponential backoff,
+// honoring any Retry-After/RateLimit-Remaining hint from the previous
+// attempt, up to maxFetchAttempts.
+func (c *Client) doRequest(ctx context.Context, url string) ([]byte, error) {
+	var lastErr error
+	for attempt := 1; attempt <= maxFetchAttempts; attempt++ {
+		data, retryAfter, err := c.attemptRequest(ctx, url)
+		if err == nil {
+			return data, nil
+		}
+
+		lastErr = err
+		log.Println(err.Error())
+
+		if attempt == maxFetchAttempts {
+			break
+		}
+
+		wait := backoffDuration(attempt)
+		if retryAfter > wait {
+			wait = retryAfter
+		}
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return nil, fmt.Errorf("giving up after %d attempts: %w", maxFetchAttempts, lastErr)
+}
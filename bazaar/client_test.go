@@ -0,0 +1,85 @@
+package bazaar
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientProducts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"success":true,"productIds":["INK_SACK","COBBLESTONE"]}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-key")
+	client.endpoint = srv.URL
+
+	products, err := client.Products(context.Background())
+	if err != nil {
+		t.Fatalf("Products() error = %v", err)
+	}
+	if len(products) != 2 || products[0] != "INK_SACK" || products[1] != "COBBLESTONE" {
+		t.Fatalf("Products() = %v, want [INK_SACK COBBLESTONE]", products)
+	}
+}
+
+func TestClientProduct(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"success":true,"product_info":{"quick_status":{"buyPrice":12.5,"sellPrice":10}}}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-key")
+	client.endpoint = srv.URL
+
+	price, err := client.Product(context.Background(), "COBBLESTONE")
+	if err != nil {
+		t.Fatalf("Product() error = %v", err)
+	}
+	if price.Buy != 12.5 || price.Sell != 10 {
+		t.Fatalf("Product() = %+v, want {Buy:12.5 Sell:10}", price)
+	}
+}
+
+func TestClientProductsRetriesOnServerError(t *testing.T) {
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(`{"success":true,"productIds":["INK_SACK"]}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-key")
+	client.endpoint = srv.URL
+
+	products, err := client.Products(context.Background())
+	if err != nil {
+		t.Fatalf("Products() error = %v", err)
+	}
+	if attempts < 2 {
+		t.Fatalf("expected at least 2 attempts, got %d", attempts)
+	}
+	if len(products) != 1 || products[0] != "INK_SACK" {
+		t.Fatalf("Products() = %v, want [INK_SACK]", products)
+	}
+}
+
+func TestClientProductsUnsuccessful(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"success":false}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-key")
+	client.endpoint = srv.URL
+
+	if _, err := client.Products(context.Background()); err != ErrUnsuccessful {
+		t.Fatalf("Products() error = %v, want ErrUnsuccessful", err)
+	}
+}
@@ -0,0 +1,120 @@
+// Package cache provides an HTTP caching middleware that answers
+// conditional requests (If-None-Match / If-Modified-Since) with
+// 304 Not Modified when the wrapped handler's underlying data has not
+// changed. It follows a pluggable Store interface with a TTL derived from
+// the upstream refresh cadence and a background sweeper that evicts stale
+// entries, so any handler backed by a refreshed cache can opt in.
+package cache
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Entry is the freshness state of a cached resource.
+type Entry struct {
+	ETag      string
+	UpdatedAt time.Time
+}
+
+// Source computes the current Entry for an incoming request. ok is false
+// when the wrapped handler has nothing cached yet, in which case the
+// request is passed straight through without conditional handling.
+type Source func(r *http.Request) (entry Entry, ok bool)
+
+// Store persists the last known Entry per cache key.
+type Store interface {
+	Get(key string) (Entry, bool)
+	Set(key string, entry Entry)
+	Sweep(maxAge time.Duration)
+}
+
+// MemoryStore is an in-memory Store guarded by a RWMutex.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	entries map[string]Entry
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]Entry)}
+}
+
+func (s *MemoryStore) Get(key string) (Entry, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entry, ok := s.entries[key]
+	return entry, ok
+}
+
+func (s *MemoryStore) Set(key string, entry Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = entry
+}
+
+func (s *MemoryStore) Sweep(maxAge time.Duration) {
+	cutoff := time.Now().Add(-maxAge)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, entry := range s.entries {
+		if entry.UpdatedAt.Before(cutoff) {
+			delete(s.entries, key)
+		}
+	}
+}
+
+// StartSweeper calls store.Sweep(maxAge) every interval until stop is
+// closed.
+func StartSweeper(store Store, maxAge, interval time.Duration, stop <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				store.Sweep(maxAge)
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Middleware wraps next so that a matching If-None-Match or
+// If-Modified-Since request header short-circuits into a 304, and
+// successful responses carry ETag, Last-Modified and Cache-Control
+// headers derived from ttl. source computes the current Entry for the
+// request; store just remembers the last one served, for bookkeeping by
+// the background sweeper.
+func Middleware(store Store, source Source, ttl time.Duration, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		entry, ok := source(r)
+		if !ok {
+			next(w, r)
+			return
+		}
+
+		store.Set(r.URL.Path, entry)
+
+		if inm := r.Header.Get("If-None-Match"); inm != "" && inm == entry.ETag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		if ims := r.Header.Get("If-Modified-Since"); ims != "" {
+			if t, err := http.ParseTime(ims); err == nil && !entry.UpdatedAt.After(t) {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+
+		w.Header().Set("ETag", entry.ETag)
+		w.Header().Set("Last-Modified", entry.UpdatedAt.UTC().Format(http.TimeFormat))
+		w.Header().Set("Cache-Control", "max-age="+strconv.Itoa(int(ttl.Seconds())))
+
+		next(w, r)
+	}
+}
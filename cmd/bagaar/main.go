@@ -0,0 +1,148 @@
+// Command bagaar runs the bazaar price proxy: it periodically refreshes
+// every product's price from the Hypixel API and serves it over HTTP.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/scotow/bagaar/bazaar"
+	"github.com/scotow/bagaar/cache"
+	"github.com/scotow/bagaar/server"
+	"github.com/scotow/bagaar/store"
+)
+
+const maxCallPerMinute = 120
+
+func main() {
+	apiKey := flag.String("api-key", os.Getenv("BAGAAR_API_KEY"), "Hypixel API key")
+	listen := flag.String("listen", envOr("BAGAAR_LISTEN", ":8080"), "HTTP listen address")
+	refreshInterval := flag.Duration("refresh-interval", envDurationOr("BAGAAR_REFRESH_INTERVAL", 2*time.Minute), "delay between full product refresh cycles")
+	storeSpec := flag.String("store", envOr("BAGAAR_STORE", "memory"), `backing store: "memory" or "file:<dir>"`)
+	flag.Parse()
+
+	if *apiKey == "" {
+		log.Fatalln(server.ErrCodeKeyMissing.Message())
+	}
+
+	st := openStore(*storeSpec)
+	client := bazaar.NewClient(*apiKey)
+	cacheStore := cache.NewMemoryStore()
+	srv := server.New(st, cacheStore, *refreshInterval)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		log.Println("shutdown signal received")
+		cancel()
+	}()
+
+	go updateLoop(ctx, client, st, srv, *refreshInterval)
+
+	cacheStop := make(chan struct{})
+	cache.StartSweeper(cacheStore, *refreshInterval*2, *refreshInterval, cacheStop)
+
+	httpServer := &http.Server{Addr: *listen, Handler: srv.Routes()}
+	go func() {
+		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Println(err.Error())
+		}
+	}()
+
+	<-ctx.Done()
+	close(cacheStop)
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer shutdownCancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		log.Println(err.Error())
+	}
+}
+
+// openStore builds the Store named by spec: "memory", or "file:<dir>" for
+// an ndjson-backed history log rooted at dir.
+func openStore(spec string) store.Store {
+	if dir, ok := strings.CutPrefix(spec, "file:"); ok {
+		return store.NewFileStore(dir)
+	}
+	return store.NewMemoryStore()
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func envDurationOr(key string, fallback time.Duration) time.Duration {
+	if v := os.Getenv(key); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+	return fallback
+}
+
+// sleepOrDone waits for d, returning false early if ctx is cancelled first.
+func sleepOrDone(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-time.After(d):
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func updateLoop(ctx context.Context, client *bazaar.Client, st store.Store, srv *server.Server, refreshInterval time.Duration) {
+	for ctx.Err() == nil {
+		products, err := client.Products(ctx)
+		srv.SetRateLimited(client.RateLimited())
+		if err != nil {
+			log.Println(err.Error())
+			srv.RecordError()
+			if !sleepOrDone(ctx, refreshInterval) {
+				return
+			}
+			continue
+		}
+		log.Printf("%d products loaded\n", len(products))
+
+		log.Println("Data update started")
+		for _, productId := range products {
+			price, err := client.Product(ctx, productId)
+			srv.SetRateLimited(client.RateLimited())
+			if err != nil {
+				log.Println(err.Error())
+				srv.RecordError()
+			} else {
+				now := time.Now()
+				st.SetPrice(productId, store.Price{Buy: price.Buy, Sell: price.Sell, UpdatedAt: now})
+				st.AppendHistory(productId, store.PricePoint{Ts: now, Buy: price.Buy, Sell: price.Sell})
+				srv.Publish(productId, price.Buy, price.Sell, now)
+			}
+
+			if !sleepOrDone(ctx, client.Pace(time.Minute/(maxCallPerMinute-5))) {
+				return
+			}
+		}
+
+		log.Println("Data update completed")
+		srv.RecordRefresh(len(products))
+
+		if !sleepOrDone(ctx, refreshInterval) {
+			return
+		}
+	}
+}
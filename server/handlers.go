@@ -0,0 +1,107 @@
+package server
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/scotow/bagaar/cache"
+	"github.com/scotow/bagaar/store"
+)
+
+// lastPathSegment returns the final, non-empty segment of an URL path, e.g.
+// "bar" for "/foo/bar/" and "/foo/bar".
+func lastPathSegment(path string) string {
+	parts := strings.Split(strings.TrimRight(path, "/"), "/")
+	return parts[len(parts)-1]
+}
+
+// etagForPrice derives a strong ETag from the fields that make price's
+// representation change.
+func etagForPrice(price store.Price) string {
+	h := fnv.New64a()
+	_, _ = fmt.Fprintf(h, "%.4f:%.4f:%d", price.Buy, price.Sell, price.UpdatedAt.UnixNano())
+	return fmt.Sprintf(`"%x"`, h.Sum64())
+}
+
+// priceCacheSource reports the cache.Entry for whichever product is named
+// by the request path, shared by the /buy/ and /sell/ handlers.
+func (s *Server) priceCacheSource(r *http.Request) (cache.Entry, bool) {
+	price, ok := s.store.Price(lastPathSegment(r.URL.Path))
+	if !ok {
+		return cache.Entry{}, false
+	}
+
+	return cache.Entry{ETag: etagForPrice(price), UpdatedAt: price.UpdatedAt}, true
+}
+
+// csvCacheSource reports a cache.Entry covering the whole price map, so
+// /csv can be served a 304 as long as no product has changed.
+func (s *Server) csvCacheSource(_ *http.Request) (cache.Entry, bool) {
+	prices := s.store.Prices()
+	if len(prices) == 0 {
+		return cache.Entry{}, false
+	}
+
+	ids := make([]string, 0, len(prices))
+	for id := range prices {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	h := fnv.New64a()
+	var updatedAt time.Time
+	for _, id := range ids {
+		price := prices[id]
+		_, _ = fmt.Fprintf(h, "%s:%.4f:%.4f;", id, price.Buy, price.Sell)
+		if price.UpdatedAt.After(updatedAt) {
+			updatedAt = price.UpdatedAt
+		}
+	}
+
+	return cache.Entry{ETag: fmt.Sprintf(`"%x"`, h.Sum64()), UpdatedAt: updatedAt}, true
+}
+
+type productHandler func(store.Price, http.ResponseWriter)
+
+func (s *Server) priceHandler(f productHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		productId := lastPathSegment(r.URL.Path)
+		if productId == "" {
+			writeError(w, ErrCodeInvalidProduct, "")
+			return
+		}
+
+		price, ok := s.store.Price(productId)
+		if !ok {
+			if s.isRateLimited() {
+				writeError(w, ErrCodeRateLimited, productId)
+				return
+			}
+			writeError(w, ErrCodePriceNotCached, productId)
+			return
+		}
+
+		f(price, w)
+	}
+}
+
+func buyPriceHandler(price store.Price, w http.ResponseWriter) {
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(fmt.Sprintf("%.2f", price.Sell)))
+}
+
+func sellPriceHandler(price store.Price, w http.ResponseWriter) {
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(fmt.Sprintf("%.2f", price.Buy)))
+}
+
+func (s *Server) csvHandler(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	for productId, price := range s.store.Prices() {
+		_, _ = w.Write([]byte(fmt.Sprintf("%s,%.2f,%.2f\n", productId, price.Sell, price.Buy)))
+	}
+}
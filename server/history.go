@@ -0,0 +1,163 @@
+package server
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/scotow/bagaar/store"
+)
+
+// OHLC is an open/high/low/close aggregation of buy and sell prices over
+// a resolution-sized bucket, used to answer /history queries cheaply.
+type OHLC struct {
+	Ts        time.Time `json:"ts"`
+	BuyOpen   float64   `json:"buyOpen"`
+	BuyHigh   float64   `json:"buyHigh"`
+	BuyLow    float64   `json:"buyLow"`
+	BuyClose  float64   `json:"buyClose"`
+	SellOpen  float64   `json:"sellOpen"`
+	SellHigh  float64   `json:"sellHigh"`
+	SellLow   float64   `json:"sellLow"`
+	SellClose float64   `json:"sellClose"`
+}
+
+// aggregateOHLC buckets points into resolution-sized windows and computes
+// an OHLC candle per bucket. points must be in chronological order.
+func aggregateOHLC(points []store.PricePoint, resolution time.Duration) []OHLC {
+	if resolution <= 0 || len(points) == 0 {
+		return nil
+	}
+
+	var candles []OHLC
+	var current *OHLC
+	var bucketStart time.Time
+
+	for _, p := range points {
+		ts := p.Ts.Truncate(resolution)
+		if current == nil || !ts.Equal(bucketStart) {
+			candles = append(candles, OHLC{
+				Ts:        ts,
+				BuyOpen:   p.Buy,
+				BuyHigh:   p.Buy,
+				BuyLow:    p.Buy,
+				BuyClose:  p.Buy,
+				SellOpen:  p.Sell,
+				SellHigh:  p.Sell,
+				SellLow:   p.Sell,
+				SellClose: p.Sell,
+			})
+			current = &candles[len(candles)-1]
+			bucketStart = ts
+			continue
+		}
+
+		current.BuyHigh = math.Max(current.BuyHigh, p.Buy)
+		current.BuyLow = math.Min(current.BuyLow, p.Buy)
+		current.BuyClose = p.Buy
+		current.SellHigh = math.Max(current.SellHigh, p.Sell)
+		current.SellLow = math.Min(current.SellLow, p.Sell)
+		current.SellClose = p.Sell
+	}
+
+	return candles
+}
+
+// parseTimeParam parses raw as a unix timestamp (seconds) or an RFC3339
+// string, reporting false if raw is empty or matches neither format.
+func parseTimeParam(raw string) (time.Time, bool) {
+	if raw == "" {
+		return time.Time{}, false
+	}
+	if sec, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return time.Unix(sec, 0), true
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// parsePagination reads page/pageSize from q, defaulting to the first page
+// of 100 candles when either is absent or invalid.
+func parsePagination(q url.Values) (page, pageSize int) {
+	page, pageSize = 1, 100
+	if v := q.Get("page"); v != "" {
+		if p, err := strconv.Atoi(v); err == nil && p > 0 {
+			page = p
+		}
+	}
+	if v := q.Get("pageSize"); v != "" {
+		if ps, err := strconv.Atoi(v); err == nil && ps > 0 {
+			pageSize = ps
+		}
+	}
+	return
+}
+
+func (s *Server) historyHandler(w http.ResponseWriter, r *http.Request) {
+	productId := lastPathSegment(r.URL.Path)
+	if productId == "" {
+		writeError(w, ErrCodeInvalidProduct, "")
+		return
+	}
+
+	points := s.store.History(productId)
+	if len(points) == 0 {
+		if s.isRateLimited() {
+			writeError(w, ErrCodeRateLimited, productId)
+			return
+		}
+		writeError(w, ErrCodePriceNotCached, productId)
+		return
+	}
+
+	q := r.URL.Query()
+	from, hasFrom := parseTimeParam(q.Get("from"))
+	to, hasTo := parseTimeParam(q.Get("to"))
+	if hasFrom || hasTo {
+		filtered := points[:0:0]
+		for _, p := range points {
+			if hasFrom && p.Ts.Before(from) {
+				continue
+			}
+			if hasTo && p.Ts.After(to) {
+				continue
+			}
+			filtered = append(filtered, p)
+		}
+		points = filtered
+	}
+
+	resolution := time.Minute
+	if v := q.Get("resolution"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			resolution = d
+		}
+	}
+
+	candles := aggregateOHLC(points, resolution)
+
+	if v := q.Get("limit"); v != "" {
+		if limit, err := strconv.Atoi(v); err == nil && limit > 0 && limit < len(candles) {
+			candles = candles[len(candles)-limit:]
+		}
+	}
+
+	page, pageSize := parsePagination(q)
+	start := (page - 1) * pageSize
+	if start > len(candles) {
+		start = len(candles)
+	}
+	end := start + pageSize
+	if end > len(candles) {
+		end = len(candles)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(candles[start:end])
+}
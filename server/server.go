@@ -0,0 +1,177 @@
+// Package server exposes a Store and a response cache over HTTP: /csv,
+// /buy/{productId}, /sell/{productId}, /history/{productId}, /health,
+// /stream and /stream/{productId}.
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/scotow/bagaar/cache"
+	"github.com/scotow/bagaar/store"
+)
+
+// ErrorCode identifies the class of failure behind a JSON error response,
+// letting clients branch on a stable code instead of parsing free-form text.
+type ErrorCode int
+
+const (
+	ErrCodeInvalidProduct ErrorCode = iota + 1
+	ErrCodePriceNotCached
+	ErrCodeUpstreamFailure
+	// ErrCodeRateLimited is returned by the price and history handlers in
+	// place of ErrCodePriceNotCached when the refresh loop has reported
+	// (via SetRateLimited) that the upstream API is currently throttling
+	// us, so a missing product reads as "rate-limited" rather than
+	// "never fetched".
+	ErrCodeRateLimited
+	// ErrCodeKeyMissing is never returned by an HTTP handler: a missing
+	// API key stops cmd/bagaar before the server starts listening. It is
+	// defined here, alongside its Message(), so that startup failure and
+	// in-response errors share one source of truth for the text.
+	ErrCodeKeyMissing
+)
+
+// Message returns the human-readable text for code, the same text used in
+// JsonError responses, for callers (like cmd/bagaar's startup check) that
+// need to report it outside of an HTTP response.
+func (c ErrorCode) Message() string {
+	return errorMessages[c]
+}
+
+// JsonError is the envelope returned by every HTTP endpoint on failure.
+type JsonError struct {
+	ErrorCode ErrorCode `json:"errorCode"`
+	Message   string    `json:"message"`
+	Cause     string    `json:"cause,omitempty"`
+}
+
+var errorMessages = map[ErrorCode]string{
+	ErrCodeInvalidProduct:  "invalid product ID",
+	ErrCodePriceNotCached:  "price not yet cached",
+	ErrCodeUpstreamFailure: "upstream API failure",
+	ErrCodeRateLimited:     "rate-limit exceeded",
+	ErrCodeKeyMissing:      "API key missing",
+}
+
+// newJsonError builds the JSON envelope for the given code, optionally
+// attaching cause as extra context (e.g. the underlying error string).
+func newJsonError(code ErrorCode, cause string) JsonError {
+	return JsonError{
+		ErrorCode: code,
+		Message:   errorMessages[code],
+		Cause:     cause,
+	}
+}
+
+// writeError writes a JSON error envelope with the HTTP status matching code.
+func writeError(w http.ResponseWriter, code ErrorCode, cause string) {
+	status := http.StatusInternalServerError
+	switch code {
+	case ErrCodeInvalidProduct, ErrCodePriceNotCached:
+		status = http.StatusNotFound
+	case ErrCodeUpstreamFailure:
+		status = http.StatusBadGateway
+	case ErrCodeRateLimited:
+		status = http.StatusTooManyRequests
+	case ErrCodeKeyMissing:
+		status = http.StatusUnauthorized
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(newJsonError(code, cause))
+}
+
+// HealthResponse is the payload served by /health.
+type HealthResponse struct {
+	LastRefresh    time.Time `json:"lastRefresh"`
+	ProductsLoaded int       `json:"productsLoaded"`
+	ErrorCount     int       `json:"errorCount"`
+}
+
+// Server wires a price/history Store and a response cache into an HTTP
+// handler. Health bookkeeping (last refresh, products loaded, error count)
+// is updated by the caller's refresh loop via RecordRefresh/RecordError.
+type Server struct {
+	store      store.Store
+	cacheStore cache.Store
+	ttl        time.Duration
+	hub        *hub
+
+	healthLock     sync.RWMutex
+	lastRefresh    time.Time
+	productsLoaded int
+	errorCount     int
+
+	rateLimitLock sync.RWMutex
+	rateLimited   bool
+}
+
+// New builds a Server backed by st, recording the cache entries of /csv,
+// /buy/ and /sell/ in cacheStore and advertising ttl as their
+// Cache-Control max-age.
+func New(st store.Store, cacheStore cache.Store, ttl time.Duration) *Server {
+	return &Server{store: st, cacheStore: cacheStore, ttl: ttl, hub: newHub()}
+}
+
+// RecordError increments the error counter reported by /health.
+func (s *Server) RecordError() {
+	s.healthLock.Lock()
+	s.errorCount++
+	s.healthLock.Unlock()
+}
+
+// RecordRefresh marks a completed refresh cycle that loaded products
+// products, updating the state reported by /health.
+func (s *Server) RecordRefresh(products int) {
+	s.healthLock.Lock()
+	s.lastRefresh = time.Now()
+	s.productsLoaded = products
+	s.healthLock.Unlock()
+}
+
+// SetRateLimited records whether the refresh loop currently believes the
+// upstream API is throttling requests, so price/history handlers can
+// report ErrCodeRateLimited instead of ErrCodePriceNotCached.
+func (s *Server) SetRateLimited(limited bool) {
+	s.rateLimitLock.Lock()
+	s.rateLimited = limited
+	s.rateLimitLock.Unlock()
+}
+
+func (s *Server) isRateLimited() bool {
+	s.rateLimitLock.RLock()
+	defer s.rateLimitLock.RUnlock()
+	return s.rateLimited
+}
+
+func (s *Server) healthHandler(w http.ResponseWriter, _ *http.Request) {
+	s.healthLock.RLock()
+	resp := HealthResponse{
+		LastRefresh:    s.lastRefresh,
+		ProductsLoaded: s.productsLoaded,
+		ErrorCount:     s.errorCount,
+	}
+	s.healthLock.RUnlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// Routes builds the HTTP handler serving /csv, /buy/, /sell/, /history/,
+// /health, /stream and /stream/.
+func (s *Server) Routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/csv", cache.Middleware(s.cacheStore, s.csvCacheSource, s.ttl, s.csvHandler))
+	mux.HandleFunc("/buy/", cache.Middleware(s.cacheStore, s.priceCacheSource, s.ttl, s.priceHandler(buyPriceHandler)))
+	mux.HandleFunc("/sell/", cache.Middleware(s.cacheStore, s.priceCacheSource, s.ttl, s.priceHandler(sellPriceHandler)))
+	mux.HandleFunc("/history/", s.historyHandler)
+	mux.HandleFunc("/health", s.healthHandler)
+	mux.HandleFunc("/stream", s.streamHandler)
+	mux.HandleFunc("/stream/", s.streamHandler)
+	return mux
+}
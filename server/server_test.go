@@ -0,0 +1,106 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/scotow/bagaar/cache"
+	"github.com/scotow/bagaar/store"
+)
+
+func newTestServer(st store.Store) *Server {
+	return New(st, cache.NewMemoryStore(), time.Minute)
+}
+
+func TestBuyPriceHandlerNotCached(t *testing.T) {
+	srv := newTestServer(store.NewMemoryStore())
+
+	req := httptest.NewRequest(http.MethodGet, "/buy/COBBLESTONE", nil)
+	rec := httptest.NewRecorder()
+	srv.Routes().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestBuyPriceHandlerServesCachedPrice(t *testing.T) {
+	st := store.NewMemoryStore()
+	st.SetPrice("COBBLESTONE", store.Price{Buy: 12.5, Sell: 10, UpdatedAt: time.Now()})
+	srv := newTestServer(st)
+
+	req := httptest.NewRequest(http.MethodGet, "/buy/COBBLESTONE", nil)
+	rec := httptest.NewRecorder()
+	srv.Routes().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if rec.Body.String() != "10.00" {
+		t.Fatalf("body = %q, want %q", rec.Body.String(), "10.00")
+	}
+	if rec.Header().Get("ETag") == "" {
+		t.Fatal("ETag header missing")
+	}
+}
+
+func TestPriceHandlerNotModified(t *testing.T) {
+	st := store.NewMemoryStore()
+	st.SetPrice("COBBLESTONE", store.Price{Buy: 12.5, Sell: 10, UpdatedAt: time.Now()})
+	srv := newTestServer(st)
+
+	first := httptest.NewRecorder()
+	srv.Routes().ServeHTTP(first, httptest.NewRequest(http.MethodGet, "/buy/COBBLESTONE", nil))
+
+	req := httptest.NewRequest(http.MethodGet, "/buy/COBBLESTONE", nil)
+	req.Header.Set("If-None-Match", first.Header().Get("ETag"))
+	rec := httptest.NewRecorder()
+	srv.Routes().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotModified {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotModified)
+	}
+}
+
+func TestHistoryHandler(t *testing.T) {
+	st := store.NewMemoryStore()
+	st.AppendHistory("COBBLESTONE", store.PricePoint{Ts: time.Unix(0, 0), Buy: 1, Sell: 2})
+	st.AppendHistory("COBBLESTONE", store.PricePoint{Ts: time.Unix(30, 0), Buy: 3, Sell: 4})
+	srv := newTestServer(st)
+
+	req := httptest.NewRequest(http.MethodGet, "/history/COBBLESTONE?resolution=1m", nil)
+	rec := httptest.NewRecorder()
+	srv.Routes().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d, body=%s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+}
+
+func TestHealthHandler(t *testing.T) {
+	srv := newTestServer(store.NewMemoryStore())
+	srv.RecordRefresh(5)
+
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	rec := httptest.NewRecorder()
+	srv.Routes().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestBuyPriceHandlerReportsRateLimited(t *testing.T) {
+	srv := newTestServer(store.NewMemoryStore())
+	srv.SetRateLimited(true)
+
+	req := httptest.NewRequest(http.MethodGet, "/buy/COBBLESTONE", nil)
+	rec := httptest.NewRecorder()
+	srv.Routes().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+}
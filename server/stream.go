@@ -0,0 +1,198 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	streamLogCapacity      = 1000
+	streamSubscriberBuffer = 16
+	streamHeartbeat        = 15 * time.Second
+)
+
+// StreamEvent is published to subscribers after every successful price
+// update and replayed to reconnecting clients via Last-Event-ID.
+type StreamEvent struct {
+	ID        int64     `json:"id"`
+	ProductId string    `json:"productId"`
+	Buy       float64   `json:"buy"`
+	Sell      float64   `json:"sell"`
+	Ts        time.Time `json:"ts"`
+}
+
+type streamSubscriber struct {
+	productId string // empty matches every product
+	ch        chan StreamEvent
+}
+
+// hub is a small pub/sub broker for StreamEvents, keeping a bounded log so
+// reconnecting subscribers can replay what they missed.
+type hub struct {
+	mu          sync.RWMutex
+	subscribers map[int64]*streamSubscriber
+	nextSubID   int64
+
+	logMu       sync.Mutex
+	log         []StreamEvent
+	nextEventID int64
+}
+
+func newHub() *hub {
+	return &hub{subscribers: make(map[int64]*streamSubscriber)}
+}
+
+// publish assigns event the next sequence ID, records it in the bounded
+// log, and forwards it to every matching subscriber. A subscriber whose
+// buffer is full is skipped rather than blocking the publisher.
+func (h *hub) publish(event StreamEvent) {
+	h.logMu.Lock()
+	h.nextEventID++
+	event.ID = h.nextEventID
+	h.log = append(h.log, event)
+	if len(h.log) > streamLogCapacity {
+		h.log = h.log[len(h.log)-streamLogCapacity:]
+	}
+	h.logMu.Unlock()
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, sub := range h.subscribers {
+		if sub.productId != "" && sub.productId != event.ProductId {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+		}
+	}
+}
+
+func (h *hub) subscribe(productId string) (id int64, ch chan StreamEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.nextSubID++
+	id = h.nextSubID
+	ch = make(chan StreamEvent, streamSubscriberBuffer)
+	h.subscribers[id] = &streamSubscriber{productId: productId, ch: ch}
+	return id, ch
+}
+
+func (h *hub) unsubscribe(id int64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if sub, ok := h.subscribers[id]; ok {
+		close(sub.ch)
+		delete(h.subscribers, id)
+	}
+}
+
+// eventsSince returns the logged events after lastID matching productId
+// (all products if empty), in publish order.
+func (h *hub) eventsSince(lastID int64, productId string) []StreamEvent {
+	h.logMu.Lock()
+	defer h.logMu.Unlock()
+
+	var out []StreamEvent
+	for _, event := range h.log {
+		if event.ID <= lastID {
+			continue
+		}
+		if productId != "" && event.ProductId != productId {
+			continue
+		}
+		out = append(out, event)
+	}
+	return out
+}
+
+// Publish notifies /stream subscribers of a new price for productId.
+func (s *Server) Publish(productId string, buy, sell float64, ts time.Time) {
+	s.hub.publish(StreamEvent{ProductId: productId, Buy: buy, Sell: sell, Ts: ts})
+}
+
+func writeSSEEvent(w http.ResponseWriter, event StreamEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\ndata: %s\n\n", event.ID, data)
+	return err
+}
+
+// streamHandler serves /stream and /stream/{productId} as Server-Sent
+// Events: a Last-Event-ID header replays missed events from the bounded
+// log, then new events stream as they're published. A heartbeat comment is
+// sent every streamHeartbeat to keep idle proxies from closing the
+// connection, and the subscription is cleaned up when the request context
+// is done.
+func (s *Server) streamHandler(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, ErrCodeUpstreamFailure, "streaming unsupported")
+		return
+	}
+
+	productId := ""
+	if r.URL.Path != "/stream" {
+		productId = lastPathSegment(r.URL.Path)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	// Subscribe before replaying the log, so an event published while we're
+	// still writing the replay lands in ch rather than being lost in the
+	// gap between the two. replayedThrough then lets the live loop below
+	// drop anything it already sent during replay instead of repeating it.
+	subID, ch := s.hub.subscribe(productId)
+	defer s.hub.unsubscribe(subID)
+
+	var replayedThrough int64
+	if lastEventId := r.Header.Get("Last-Event-ID"); lastEventId != "" {
+		if id, err := strconv.ParseInt(lastEventId, 10, 64); err == nil {
+			for _, event := range s.hub.eventsSince(id, productId) {
+				if err := writeSSEEvent(w, event); err != nil {
+					return
+				}
+				replayedThrough = event.ID
+			}
+			flusher.Flush()
+		}
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case event, ok := <-ch:
+			if !ok {
+				return
+			}
+			if event.ID <= replayedThrough {
+				continue
+			}
+			if err := writeSSEEvent(w, event); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scotow/bagaar/cache"
+	"github.com/scotow/bagaar/store"
+)
+
+func TestHubPublishFiltersByProduct(t *testing.T) {
+	h := newHub()
+
+	_, all := h.subscribe("")
+	_, cobble := h.subscribe("COBBLESTONE")
+
+	h.publish(StreamEvent{ProductId: "INK_SACK"})
+
+	select {
+	case <-all:
+	default:
+		t.Fatal("unfiltered subscriber did not receive event")
+	}
+	select {
+	case <-cobble:
+		t.Fatal("filtered subscriber received event for a different product")
+	default:
+	}
+}
+
+func TestHubEventsSinceReplaysLog(t *testing.T) {
+	h := newHub()
+	h.publish(StreamEvent{ProductId: "A"})
+	h.publish(StreamEvent{ProductId: "B"})
+	h.publish(StreamEvent{ProductId: "A"})
+
+	events := h.eventsSince(1, "A")
+	if len(events) != 1 || events[0].ProductId != "A" || events[0].ID != 3 {
+		t.Fatalf("eventsSince(1, \"A\") = %+v, want one event with ID 3", events)
+	}
+}
+
+func TestStreamHandlerReplaysWithoutGapOrDuplicate(t *testing.T) {
+	srv := New(store.NewMemoryStore(), cache.NewMemoryStore(), time.Minute)
+	srv.Publish("COBBLESTONE", 1, 2, time.Now())
+
+	httpSrv := httptest.NewServer(srv.Routes())
+	defer httpSrv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, httpSrv.URL+"/stream/COBBLESTONE", nil)
+	if err != nil {
+		t.Fatalf("NewRequestWithContext() error = %v", err)
+	}
+	req.Header.Set("Last-Event-ID", "0")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	time.Sleep(50 * time.Millisecond)
+	srv.Publish("COBBLESTONE", 3, 4, time.Now())
+
+	var ids []string
+	reader := bufio.NewReader(resp.Body)
+	for len(ids) < 2 {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("ReadString() error = %v, got ids %v before EOF", err, ids)
+		}
+		if strings.HasPrefix(line, "id: ") {
+			ids = append(ids, strings.TrimSpace(strings.TrimPrefix(line, "id: ")))
+		}
+	}
+
+	if ids[0] == ids[1] {
+		t.Fatalf("replay and live event shared the same ID %q: the reconnect window dropped or duplicated an event", ids[0])
+	}
+	if ids[0] != "1" || ids[1] != "2" {
+		t.Fatalf("ids = %v, want [1 2]", ids)
+	}
+}
+
+func TestStreamHandlerPublishesToSubscriber(t *testing.T) {
+	srv := New(store.NewMemoryStore(), cache.NewMemoryStore(), time.Minute)
+	httpSrv := httptest.NewServer(srv.Routes())
+	defer httpSrv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, httpSrv.URL+"/stream/COBBLESTONE", nil)
+	if err != nil {
+		t.Fatalf("NewRequestWithContext() error = %v", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	// Give the handler time to subscribe before publishing.
+	time.Sleep(50 * time.Millisecond)
+	srv.Publish("COBBLESTONE", 1, 2, time.Now())
+
+	reader := bufio.NewReader(resp.Body)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("ReadString() error = %v before seeing event data", err)
+		}
+		if strings.HasPrefix(line, "data: ") {
+			if !strings.Contains(line, "COBBLESTONE") {
+				t.Fatalf("event line = %q, want it to mention COBBLESTONE", line)
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileStore is a MemoryStore that additionally persists every recorded
+// history sample as a line of JSON under dir/<productId>.ndjson, loading
+// those files back in on NewFileStore so history survives a restart. It is
+// the only persistent Store implementation; there is no SQL-backed Store.
+type FileStore struct {
+	*MemoryStore
+	dir string
+}
+
+var _ Store = (*FileStore)(nil)
+
+// NewFileStore builds a FileStore rooted at dir, replaying any
+// dir/<productId>.ndjson files already on disk into the in-memory store.
+func NewFileStore(dir string) *FileStore {
+	s := &FileStore{MemoryStore: NewMemoryStore(), dir: dir}
+	s.load()
+	return s
+}
+
+// load replays every dir/<productId>.ndjson file into the in-memory store.
+// Read errors are logged and otherwise ignored, same as AppendHistory's
+// write-side failures: a cold store is better than a refusal to start.
+func (s *FileStore) load() {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Println(err.Error())
+		}
+		return
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".ndjson") {
+			continue
+		}
+
+		productId := strings.TrimSuffix(entry.Name(), ".ndjson")
+		if err := s.loadProduct(productId); err != nil {
+			log.Println(err.Error())
+		}
+	}
+}
+
+func (s *FileStore) loadProduct(productId string) error {
+	f, err := os.Open(filepath.Join(s.dir, productId+".ndjson"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var last PricePoint
+	var found bool
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var point PricePoint
+		if err := json.Unmarshal(scanner.Bytes(), &point); err != nil {
+			continue
+		}
+
+		s.MemoryStore.AppendHistory(productId, point)
+		last, found = point, true
+	}
+
+	if found {
+		s.MemoryStore.SetPrice(productId, Price{Buy: last.Buy, Sell: last.Sell, UpdatedAt: last.Ts})
+	}
+
+	return scanner.Err()
+}
+
+func (s *FileStore) AppendHistory(productId string, point PricePoint) {
+	s.MemoryStore.AppendHistory(productId, point)
+
+	if err := s.appendLog(productId, point); err != nil {
+		log.Println(err.Error())
+	}
+}
+
+func (s *FileStore) appendLog(productId string, point PricePoint) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(filepath.Join(s.dir, productId+".ndjson"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	data, err := json.Marshal(point)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	_, err = f.Write(data)
+	return err
+}
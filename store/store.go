@@ -0,0 +1,98 @@
+// Package store holds the latest bazaar price and bounded price history per
+// product behind a Store interface, so the server package can be backed by
+// different persistence strategies.
+package store
+
+import (
+	"sync"
+	"time"
+)
+
+// HistoryCapacity bounds how many PricePoint samples are kept per product.
+const HistoryCapacity = 1000
+
+// Price is a product's latest observed buy/sell values.
+type Price struct {
+	Buy       float64
+	Sell      float64
+	UpdatedAt time.Time
+}
+
+// PricePoint is a single buy/sell sample recorded at Ts.
+type PricePoint struct {
+	Ts   time.Time `json:"ts"`
+	Buy  float64   `json:"buy"`
+	Sell float64   `json:"sell"`
+}
+
+// Store is the persistence boundary for latest prices and their history.
+type Store interface {
+	SetPrice(productId string, price Price)
+	Price(productId string) (Price, bool)
+	Prices() map[string]Price
+	AppendHistory(productId string, point PricePoint)
+	History(productId string) []PricePoint
+}
+
+// MemoryStore keeps latest prices and a bounded history ring buffer per
+// product in memory only.
+type MemoryStore struct {
+	priceLock sync.RWMutex
+	prices    map[string]Price
+
+	historyLock sync.RWMutex
+	history     map[string][]PricePoint
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		prices:  make(map[string]Price),
+		history: make(map[string][]PricePoint),
+	}
+}
+
+func (s *MemoryStore) SetPrice(productId string, price Price) {
+	s.priceLock.Lock()
+	defer s.priceLock.Unlock()
+	s.prices[productId] = price
+}
+
+func (s *MemoryStore) Price(productId string) (Price, bool) {
+	s.priceLock.RLock()
+	defer s.priceLock.RUnlock()
+	p, ok := s.prices[productId]
+	return p, ok
+}
+
+func (s *MemoryStore) Prices() map[string]Price {
+	s.priceLock.RLock()
+	defer s.priceLock.RUnlock()
+
+	out := make(map[string]Price, len(s.prices))
+	for id, p := range s.prices {
+		out[id] = p
+	}
+	return out
+}
+
+func (s *MemoryStore) AppendHistory(productId string, point PricePoint) {
+	s.historyLock.Lock()
+	defer s.historyLock.Unlock()
+
+	points := append(s.history[productId], point)
+	if len(points) > HistoryCapacity {
+		points = points[len(points)-HistoryCapacity:]
+	}
+	s.history[productId] = points
+}
+
+func (s *MemoryStore) History(productId string) []PricePoint {
+	s.historyLock.RLock()
+	defer s.historyLock.RUnlock()
+
+	points := make([]PricePoint, len(s.history[productId]))
+	copy(points, s.history[productId])
+	return points
+}
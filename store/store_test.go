@@ -0,0 +1,80 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMemoryStorePrice(t *testing.T) {
+	s := NewMemoryStore()
+
+	if _, ok := s.Price("COBBLESTONE"); ok {
+		t.Fatalf("Price() on empty store reported ok=true")
+	}
+
+	want := Price{Buy: 1, Sell: 2, UpdatedAt: time.Now()}
+	s.SetPrice("COBBLESTONE", want)
+
+	got, ok := s.Price("COBBLESTONE")
+	if !ok || got != want {
+		t.Fatalf("Price() = %+v, %v, want %+v, true", got, ok, want)
+	}
+}
+
+func TestMemoryStoreHistoryBounded(t *testing.T) {
+	s := NewMemoryStore()
+
+	for i := 0; i < HistoryCapacity+10; i++ {
+		s.AppendHistory("COBBLESTONE", PricePoint{Ts: time.Unix(int64(i), 0)})
+	}
+
+	points := s.History("COBBLESTONE")
+	if len(points) != HistoryCapacity {
+		t.Fatalf("len(History()) = %d, want %d", len(points), HistoryCapacity)
+	}
+	if points[0].Ts.Unix() != 10 {
+		t.Fatalf("oldest retained sample = %d, want 10", points[0].Ts.Unix())
+	}
+}
+
+func TestFileStorePersistsHistory(t *testing.T) {
+	dir := t.TempDir()
+	s := NewFileStore(dir)
+
+	point := PricePoint{Ts: time.Unix(100, 0), Buy: 1, Sell: 2}
+	s.AppendHistory("COBBLESTONE", point)
+
+	data, err := filepath.Glob(filepath.Join(dir, "*.ndjson"))
+	if err != nil {
+		t.Fatalf("Glob() error = %v", err)
+	}
+	if len(data) != 1 {
+		t.Fatalf("found %d ndjson files, want 1", len(data))
+	}
+
+	points := s.History("COBBLESTONE")
+	if len(points) != 1 || points[0] != point {
+		t.Fatalf("History() = %v, want [%+v]", points, point)
+	}
+}
+
+func TestFileStoreReloadsHistoryOnRestart(t *testing.T) {
+	dir := t.TempDir()
+
+	first := NewFileStore(dir)
+	first.AppendHistory("COBBLESTONE", PricePoint{Ts: time.Unix(100, 0), Buy: 1, Sell: 2})
+	first.AppendHistory("COBBLESTONE", PricePoint{Ts: time.Unix(200, 0), Buy: 3, Sell: 4})
+
+	reopened := NewFileStore(dir)
+
+	points := reopened.History("COBBLESTONE")
+	if len(points) != 2 || points[1].Buy != 3 {
+		t.Fatalf("History() after reload = %v, want 2 points ending with Buy=3", points)
+	}
+
+	price, ok := reopened.Price("COBBLESTONE")
+	if !ok || price.Buy != 3 || price.Sell != 4 {
+		t.Fatalf("Price() after reload = %+v, %v, want the last persisted sample", price, ok)
+	}
+}